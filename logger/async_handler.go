@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what AsyncHandler does once its queue is full.
+type OverflowPolicy int
+
+const (
+	// Block waits for room in the queue, applying backpressure to the caller.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest queued record to make room for the new one.
+	DropOldest
+	// DropNewest discards the incoming record, leaving the queue untouched.
+	DropNewest
+)
+
+// AsyncHandlerOptions configures AsyncHandler.
+type AsyncHandlerOptions struct {
+	// QueueSize bounds the number of records buffered between Handle and the workers.
+	// Defaults to 1024.
+	QueueSize int
+	// Workers is the number of goroutines draining the queue into the wrapped handler.
+	// Defaults to 1.
+	Workers int
+	// OverflowPolicy governs what happens when the queue is full. Defaults to Block.
+	OverflowPolicy OverflowPolicy
+}
+
+// AsyncHandlerStats reports AsyncHandler's lifetime counters.
+type AsyncHandlerStats struct {
+	Enqueued int64
+	Dropped  int64
+	Flushed  int64
+}
+
+// asyncEntry is a record plus the context values addRequestId needs, snapshotted before
+// enqueue, and the specific derived handler (from WithAttrs/WithGroup) it must be delivered
+// to, since every AsyncHandler sharing a core can have a different next.
+type asyncEntry struct {
+	next         slog.Handler
+	record       slog.Record
+	correlation  string
+	method       string
+	path         string
+	userAgent    string
+	traceID      string
+	spanID       string
+	traceFlags   string
+	parentSpanID string
+}
+
+// asyncCore is the queue and worker pool shared by every AsyncHandler derived from the same
+// NewAsyncHandler call, so WithAttrs/WithGroup don't each spin up their own goroutines that
+// Shutdown on the root handler would never see.
+type asyncCore struct {
+	opts AsyncHandlerOptions
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []asyncEntry
+	closed bool
+	wg     sync.WaitGroup
+
+	enqueued atomic.Int64
+	dropped  atomic.Int64
+	flushed  atomic.Int64
+}
+
+// AsyncHandler wraps any slog.Handler (including ContextHandler) to move its I/O off the
+// calling goroutine, via a bounded queue drained by a pool of worker goroutines.
+type AsyncHandler struct {
+	next slog.Handler
+	core *asyncCore
+}
+
+// NewAsyncHandler builds an AsyncHandler wrapping next and starts its worker pool.
+func NewAsyncHandler(next slog.Handler, opts AsyncHandlerOptions) *AsyncHandler {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	core := &asyncCore{opts: opts, queue: make([]asyncEntry, 0, opts.QueueSize)}
+	core.cond = sync.NewCond(&core.mu)
+	for i := 0; i < opts.Workers; i++ {
+		core.wg.Add(1)
+		go core.worker()
+	}
+	return &AsyncHandler{next: next, core: core}
+}
+
+// Enabled implements slog.Handler.
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle snapshots everything the worker will need before returning: r.Clone(), because
+// slog.Record holds a PC and an attribute slice that may reference caller-owned memory, and
+// the request-scoped context values the wrapped handler reads, because by the time a worker
+// picks this entry up the request that produced ctx may already have returned and its
+// context values can no longer be read safely.
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	entry := asyncEntry{
+		next:         h.next,
+		record:       r.Clone(),
+		correlation:  getDefaultValueFromContext(ctx, "correlation_id"),
+		method:       getDefaultValueFromContext(ctx, "request_method"),
+		path:         getDefaultValueFromContext(ctx, "request_path"),
+		userAgent:    getDefaultValueFromContext(ctx, "request_user_agent"),
+		traceID:      getDefaultValueFromContext(ctx, "trace_id"),
+		spanID:       getDefaultValueFromContext(ctx, "span_id"),
+		traceFlags:   getDefaultValueFromContext(ctx, "trace_flags"),
+		parentSpanID: getDefaultValueFromContext(ctx, "parent_span_id"),
+	}
+	h.core.enqueue(entry)
+	return nil
+}
+
+func (c *asyncCore) enqueue(entry asyncEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		c.dropped.Add(1)
+		return
+	}
+
+	for len(c.queue) >= c.opts.QueueSize {
+		switch c.opts.OverflowPolicy {
+		case DropNewest:
+			c.dropped.Add(1)
+			return
+		case DropOldest:
+			c.queue = c.queue[1:]
+			c.dropped.Add(1)
+		default: // Block
+			c.cond.Wait()
+			if c.closed {
+				c.dropped.Add(1)
+				return
+			}
+		}
+	}
+
+	c.queue = append(c.queue, entry)
+	c.enqueued.Add(1)
+	c.cond.Signal()
+}
+
+func (c *asyncCore) worker() {
+	defer c.wg.Done()
+	for {
+		c.mu.Lock()
+		for len(c.queue) == 0 && !c.closed {
+			c.cond.Wait()
+		}
+		if len(c.queue) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		entry := c.queue[0]
+		c.queue = c.queue[1:]
+		c.cond.Signal() // wake anything blocked on a full queue
+		c.mu.Unlock()
+
+		c.deliver(entry)
+	}
+}
+
+func (c *asyncCore) deliver(entry asyncEntry) {
+	ctx := context.WithValue(context.Background(), "correlation_id", entry.correlation)
+	ctx = context.WithValue(ctx, "request_method", entry.method)
+	ctx = context.WithValue(ctx, "request_path", entry.path)
+	ctx = context.WithValue(ctx, "request_user_agent", entry.userAgent)
+	ctx = context.WithValue(ctx, "trace_id", entry.traceID)
+	ctx = context.WithValue(ctx, "span_id", entry.spanID)
+	ctx = context.WithValue(ctx, "trace_flags", entry.traceFlags)
+	ctx = context.WithValue(ctx, "parent_span_id", entry.parentSpanID)
+
+	if err := entry.next.Handle(ctx, entry.record); err == nil {
+		c.flushed.Add(1)
+	}
+}
+
+// WithAttrs implements slog.Handler, sharing the same queue and worker pool as h.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), core: h.core}
+}
+
+// WithGroup implements slog.Handler, sharing the same queue and worker pool as h.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), core: h.core}
+}
+
+// Stats returns a snapshot of the shared core's lifetime counters.
+func (h *AsyncHandler) Stats() AsyncHandlerStats {
+	return AsyncHandlerStats{
+		Enqueued: h.core.enqueued.Load(),
+		Dropped:  h.core.dropped.Load(),
+		Flushed:  h.core.flushed.Load(),
+	}
+}
+
+// Shutdown stops accepting new records, drains whatever is already queued, and waits for
+// every worker sharing this handler's core to exit, returning early with ctx's error if its
+// deadline elapses first. It affects every AsyncHandler derived from the same
+// NewAsyncHandler call, not just h.
+func (h *AsyncHandler) Shutdown(ctx context.Context) error {
+	c := h.core
+	c.mu.Lock()
+	c.closed = true
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
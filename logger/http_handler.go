@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HTTPHandlerOptions configures HTTPHandler's batching and delivery behavior.
+type HTTPHandlerOptions struct {
+	// URL is the webhook endpoint records are POSTed to.
+	URL string
+	// Client sends each batch; defaults to http.DefaultClient.
+	Client *http.Client
+	// FlushInterval is the longest a batch waits before being sent even if MaxBatchSize
+	// hasn't been reached. Defaults to 5s.
+	FlushInterval time.Duration
+	// MaxBatchSize is the number of records that triggers an immediate flush. Defaults to 100.
+	MaxBatchSize int
+	// MaxRetries is how many times a failed POST is retried. Defaults to 3.
+	MaxRetries int
+	// RetryBaseDelay is the backoff base; the nth retry waits RetryBaseDelay*2^(n-1).
+	// Defaults to 500ms.
+	RetryBaseDelay time.Duration
+	// HandlerOptions is forwarded to the underlying slog.JSONHandler used to encode records.
+	HandlerOptions *slog.HandlerOptions
+}
+
+// HTTPHandler batches records and POSTs them as an NDJSON body to a webhook, flushing on a
+// timer or once a batch fills up, and retrying failed deliveries with exponential backoff.
+type HTTPHandler struct {
+	opts HTTPHandlerOptions
+
+	mu    sync.Mutex
+	batch bytes.Buffer
+	count int
+
+	done  chan struct{}
+	wg    sync.WaitGroup
+	inner slog.Handler
+}
+
+// NewHTTPHandler builds an HTTPHandler per opts and starts its background flush loop.
+func NewHTTPHandler(opts HTTPHandlerOptions) *HTTPHandler {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.MaxBatchSize <= 0 {
+		opts.MaxBatchSize = 100
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.RetryBaseDelay <= 0 {
+		opts.RetryBaseDelay = 500 * time.Millisecond
+	}
+
+	h := &HTTPHandler{opts: opts, done: make(chan struct{})}
+	h.inner = slog.NewJSONHandler(h, opts.HandlerOptions)
+
+	h.wg.Add(1)
+	go h.flushLoop()
+	return h
+}
+
+// Write implements io.Writer, so HTTPHandler is the sink the underlying JSON handler writes
+// into; it tracks the batch size and flushes once MaxBatchSize is reached.
+func (h *HTTPHandler) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	n, err := h.batch.Write(p)
+	if err == nil {
+		h.count++
+		if h.count >= h.opts.MaxBatchSize {
+			h.flushLocked()
+		}
+	}
+	h.mu.Unlock()
+	return n, err
+}
+
+func (h *HTTPHandler) flushLoop() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(h.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.mu.Lock()
+			h.flushLocked()
+			h.mu.Unlock()
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// flushLocked must be called with h.mu held. It hands the current batch off to a background
+// delivery attempt and resets the buffer for the next one.
+func (h *HTTPHandler) flushLocked() {
+	if h.count == 0 {
+		return
+	}
+	payload := make([]byte, h.batch.Len())
+	copy(payload, h.batch.Bytes())
+	h.batch.Reset()
+	h.count = 0
+
+	h.wg.Add(1)
+	go h.deliver(payload)
+}
+
+func (h *HTTPHandler) deliver(payload []byte) {
+	defer h.wg.Done()
+	delay := h.opts.RetryBaseDelay
+	for attempt := 0; attempt <= h.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if h.tryDeliver(payload) {
+			return
+		}
+	}
+}
+
+func (h *HTTPHandler) tryDeliver(payload []byte) bool {
+	req, err := http.NewRequest(http.MethodPost, h.opts.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := h.opts.Client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode < 500
+}
+
+// Enabled implements slog.Handler.
+func (h *HTTPHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *HTTPHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler. The returned handler still writes through h, so
+// batching and flushing stay correct regardless of how many derived handlers are in use.
+func (h *HTTPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.inner.WithAttrs(attrs)
+}
+
+// WithGroup implements slog.Handler. The returned handler still writes through h, so
+// batching and flushing stay correct regardless of how many derived handlers are in use.
+func (h *HTTPHandler) WithGroup(name string) slog.Handler {
+	return h.inner.WithGroup(name)
+}
+
+// Close stops the background flush loop and synchronously sends any remaining batch.
+func (h *HTTPHandler) Close() error {
+	close(h.done)
+	h.mu.Lock()
+	h.flushLocked()
+	h.mu.Unlock()
+	h.wg.Wait()
+	return nil
+}
@@ -8,6 +8,54 @@ import (
 // ContextHandler is our base context handler, it will handle all requests
 type ContextHandler struct {
 	slog.Handler
+
+	// redaction holds the PII redaction rules, if any, registered via NewContextHandler.
+	// Its zero value is inactive, so constructing ContextHandler as a struct literal keeps
+	// working exactly as before.
+	redaction redactionPipeline
+
+	// spanFromContext, if set, supplies the active trace/span IDs from a tracing SDK the
+	// caller already runs, overriding whatever CorrelationId/traceparent parsing stored.
+	spanFromContext SpanFromContextFunc
+}
+
+// Option configures a ContextHandler built via NewContextHandler.
+type Option func(*ContextHandler)
+
+// WithRedactor registers a redaction rule: whenever matcher matches an attribute's key,
+// strategy is used to redact its value.
+func WithRedactor(matcher KeyMatcher, strategy RedactStrategy) Option {
+	return func(ch *ContextHandler) {
+		ch.redaction.keyRedactors = append(ch.redaction.keyRedactors, keyRedactor{matcher: matcher, strategy: strategy})
+	}
+}
+
+// WithRedactKeys is a convenience over WithRedactor: it glob-matches each pattern (tried
+// against both an attribute's full dotted group path and its leaf key, e.g. "*.secret",
+// "*password*", "branch-secret") and fully replaces matching values with "***".
+func WithRedactKeys(patterns ...string) Option {
+	return func(ch *ContextHandler) {
+		for _, pattern := range patterns {
+			ch.redaction.keyRedactors = append(ch.redaction.keyRedactors, keyRedactor{matcher: KeyGlob(pattern), strategy: RedactFull()})
+		}
+	}
+}
+
+// WithSpanFromContext registers a SpanFromContextFunc so trace_id/span_id can be sourced
+// from an existing tracing SDK instead of (or in addition to) traceparent propagation.
+func WithSpanFromContext(fn SpanFromContextFunc) Option {
+	return func(ch *ContextHandler) {
+		ch.spanFromContext = fn
+	}
+}
+
+// NewContextHandler builds a ContextHandler wrapping base, applying any Options given.
+func NewContextHandler(base slog.Handler, opts ...Option) ContextHandler {
+	ch := ContextHandler{Handler: base}
+	for _, opt := range opts {
+		opt(&ch)
+	}
+	return ch
 }
 
 // Enabled determines if to log or not log, if it returns true then Handle will log
@@ -18,17 +66,18 @@ func (ch ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
 // Handle backend for api, this will be used to configure how the logs will be structured
 func (ch ContextHandler) Handle(ctx context.Context, r slog.Record) error {
 	r.AddAttrs(ch.addRequestId(ctx)...)
+	r = ch.redaction.apply(r)
 	return ch.Handler.Handle(ctx, r)
 }
 
 // WithAttrs overriding default implementation otherwise it will call the starting JSON Handler
 func (ch ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return ContextHandler{ch.Handler.WithAttrs(attrs)}
+	return ContextHandler{Handler: ch.Handler.WithAttrs(attrs), redaction: ch.redaction, spanFromContext: ch.spanFromContext}
 }
 
 // WithGroup overriding default implementation otherwise it will call the starting JSON Handler
 func (ch ContextHandler) WithGroup(name string) slog.Handler {
-	return ContextHandler{ch.Handler.WithGroup(name)}
+	return ContextHandler{Handler: ch.Handler.WithGroup(name), redaction: ch.redaction, spanFromContext: ch.spanFromContext}
 }
 
 func (ch ContextHandler) addRequestId(ctx context.Context) []slog.Attr {
@@ -38,11 +87,36 @@ func (ch ContextHandler) addRequestId(ctx context.Context) []slog.Attr {
 	path := getDefaultValueFromContext(ctx, "request_path")
 	agent := getDefaultValueFromContext(ctx, "request_user_agent")
 
-	group := slog.Group("meta_information", slog.String("correlation_id", correlation),
+	traceID := getDefaultValueFromContext(ctx, "trace_id")
+	spanID := getDefaultValueFromContext(ctx, "span_id")
+	flags := getDefaultValueFromContext(ctx, "trace_flags")
+	parentSpanID := getDefaultValueFromContext(ctx, "parent_span_id")
+	if ch.spanFromContext != nil {
+		if sdkTraceID, sdkSpanID, ok := ch.spanFromContext(ctx); ok {
+			traceID, spanID = sdkTraceID, sdkSpanID
+		}
+	}
+
+	args := []any{
+		slog.String("correlation_id", correlation),
 		slog.String("request_method", method),
 		slog.String("request_path", path),
-		slog.String("request_user_agent", agent))
-	as = append(as, group)
+		slog.String("request_user_agent", agent),
+	}
+	if traceID != "" {
+		args = append(args, slog.String("trace_id", traceID))
+	}
+	if spanID != "" {
+		args = append(args, slog.String("span_id", spanID))
+	}
+	if flags != "" {
+		args = append(args, slog.String("trace_flags", flags))
+	}
+	if parentSpanID != "" {
+		args = append(args, slog.String("parent_span_id", parentSpanID))
+	}
+
+	as = append(as, slog.Group("meta_information", args...))
 	return as
 }
 
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// SinkLevel pairs a slog.Handler sink with an independent level filter, so e.g. INFO+ can go
+// to stdout while only ERROR+ goes to a file. A nil Level means "whatever the sink itself
+// enables" (its own slog.HandlerOptions.Level).
+type SinkLevel struct {
+	Handler slog.Handler
+	Level   slog.Leveler
+}
+
+// MultiHandler fans a record out to every sink whose level filter allows it.
+type MultiHandler struct {
+	sinks []SinkLevel
+}
+
+// NewMultiHandler builds a MultiHandler dispatching to each of sinks.
+func NewMultiHandler(sinks ...SinkLevel) *MultiHandler {
+	return &MultiHandler{sinks: sinks}
+}
+
+// Enabled reports whether any sink would handle level.
+func (m *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, s := range m.sinks {
+		if m.sinkEnabled(ctx, s, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MultiHandler) sinkEnabled(ctx context.Context, s SinkLevel, level slog.Level) bool {
+	if s.Level != nil && level < s.Level.Level() {
+		return false
+	}
+	return s.Handler.Enabled(ctx, level)
+}
+
+// Handle dispatches r to every sink whose level permits it. The slog.Record documentation
+// requires passing each Handler its own copy when forwarding the same record to more than
+// one, since a Handle call (including one made by a wrapping Handler we have no visibility
+// into) may call Record.AddAttrs and mutate shared backing arrays; MultiHandler can't tell
+// which, if any, of its sinks do that, so it conservatively clones for every sink after the
+// first rather than risking corruption between sinks.
+func (m *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs error
+	given := false
+	for _, s := range m.sinks {
+		if !m.sinkEnabled(ctx, s, r.Level) {
+			continue
+		}
+		rec := r
+		if given {
+			rec = r.Clone()
+		}
+		given = true
+		if err := s.Handler.Handle(ctx, rec); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// WithAttrs fans out to every sink so they all see the same derived state.
+func (m *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make([]SinkLevel, len(m.sinks))
+	for i, s := range m.sinks {
+		next[i] = SinkLevel{Handler: s.Handler.WithAttrs(attrs), Level: s.Level}
+	}
+	return &MultiHandler{sinks: next}
+}
+
+// WithGroup fans out to every sink so they all see the same derived state.
+func (m *MultiHandler) WithGroup(name string) slog.Handler {
+	next := make([]SinkLevel, len(m.sinks))
+	for i, s := range m.sinks {
+		next[i] = SinkLevel{Handler: s.Handler.WithGroup(name), Level: s.Level}
+	}
+	return &MultiHandler{sinks: next}
+}
@@ -0,0 +1,313 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	gopath "path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RedactStrategy turns a sensitive value's string representation into its redacted form.
+type RedactStrategy func(value string) string
+
+// RedactFull replaces the value entirely with "***".
+func RedactFull() RedactStrategy {
+	return func(string) string { return "***" }
+}
+
+// RedactHash replaces the value with a prefix of the hex-encoded SHA-256 digest of its
+// string representation. A prefixLen <= 0 (or larger than the digest) returns the full digest.
+func RedactHash(prefixLen int) RedactStrategy {
+	return func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		hexSum := hex.EncodeToString(sum[:])
+		if prefixLen <= 0 || prefixLen > len(hexSum) {
+			return hexSum
+		}
+		return hexSum[:prefixLen]
+	}
+}
+
+// RedactMaskLast masks every character of the value except the last n, e.g.
+// RedactMaskLast(4) turns "1234567890" into "******7890".
+func RedactMaskLast(n int) RedactStrategy {
+	return func(value string) string {
+		if n < 0 {
+			n = 0
+		}
+		if n >= len(value) {
+			return value
+		}
+		return strings.Repeat("*", len(value)-n) + value[len(value)-n:]
+	}
+}
+
+// KeyMatcher decides whether an attribute, identified by its dotted group path
+// (e.g. "meta_information.correlation_id") or its leaf key, should be redacted.
+type KeyMatcher interface {
+	Match(path, leaf string) bool
+}
+
+// KeyGlob matches attribute keys with a shell-style glob, e.g. "*.secret" or "*password*".
+// It is tried against both the full dotted path and the leaf key name.
+func KeyGlob(pattern string) KeyMatcher {
+	return globMatcher(pattern)
+}
+
+type globMatcher string
+
+func (g globMatcher) Match(path, leaf string) bool {
+	if ok, err := gopath.Match(string(g), path); err == nil && ok {
+		return true
+	}
+	ok, _ := gopath.Match(string(g), leaf)
+	return ok
+}
+
+// KeyRegexp matches attribute keys whose full dotted path satisfies the given regular expression.
+func KeyRegexp(pattern string) KeyMatcher {
+	return regexMatcher{re: regexp.MustCompile(pattern)}
+}
+
+type regexMatcher struct{ re *regexp.Regexp }
+
+func (r regexMatcher) Match(path, leaf string) bool {
+	return r.re.MatchString(path) || r.re.MatchString(leaf)
+}
+
+// keyRedactor binds a KeyMatcher to the strategy applied when it matches.
+type keyRedactor struct {
+	matcher  KeyMatcher
+	strategy RedactStrategy
+}
+
+// redactionPipeline is the set of redaction rules attached to a ContextHandler. The zero
+// value has no rules and is a no-op, so ContextHandler{Handler: h} keeps working unchanged.
+type redactionPipeline struct {
+	keyRedactors []keyRedactor
+}
+
+func (p *redactionPipeline) active() bool {
+	return p != nil && len(p.keyRedactors) > 0
+}
+
+func (p *redactionPipeline) strategyFor(path, leaf string) RedactStrategy {
+	for _, kr := range p.keyRedactors {
+		if kr.matcher.Match(path, leaf) {
+			return kr.strategy
+		}
+	}
+	return nil
+}
+
+// apply walks r's attributes and returns a record with matching attributes redacted. If
+// nothing matches it returns r unmodified, so the common case allocates nothing beyond the
+// read-only scan below.
+func (p *redactionPipeline) apply(r slog.Record) slog.Record {
+	if !p.active() {
+		return r
+	}
+
+	dirty := false
+	r.Attrs(func(a slog.Attr) bool {
+		if p.needsRedaction("", a) {
+			dirty = true
+			return false
+		}
+		return true
+	})
+	if !dirty {
+		return r
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		na, _ := p.redactAttr("", a)
+		out.AddAttrs(na)
+		return true
+	})
+	return out
+}
+
+func (p *redactionPipeline) needsRedaction(prefix string, a slog.Attr) bool {
+	full := joinPath(prefix, a.Key)
+	v := a.Value
+	if v.Kind() == slog.KindLogValuer {
+		v = v.Resolve()
+	}
+	if v.Kind() == slog.KindGroup {
+		for _, ga := range v.Group() {
+			if p.needsRedaction(full, ga) {
+				return true
+			}
+		}
+		return false
+	}
+	if p.strategyFor(full, a.Key) != nil {
+		return true
+	}
+	if v.Kind() == slog.KindAny {
+		if t := reflect.TypeOf(derefAny(v.Any())); len(taggedFields(t)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *redactionPipeline) redactAttr(prefix string, a slog.Attr) (slog.Attr, bool) {
+	full := joinPath(prefix, a.Key)
+	v := a.Value
+	if v.Kind() == slog.KindLogValuer {
+		// Resolve so key rules and struct tags can see what LogValue() actually produced.
+		// If nothing below ends up changing, we still return the original, unresolved a so
+		// a non-matching LogValuer attr stays lazy for the wrapped handler.
+		v = v.Resolve()
+	}
+
+	if v.Kind() == slog.KindGroup {
+		attrs := v.Group()
+		out := make([]slog.Attr, len(attrs))
+		changed := false
+		for i, ga := range attrs {
+			na, did := p.redactAttr(full, ga)
+			out[i] = na
+			changed = changed || did
+		}
+		if !changed {
+			return a, false
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}, true
+	}
+
+	if strategy := p.strategyFor(full, a.Key); strategy != nil {
+		return slog.Attr{Key: a.Key, Value: slog.StringValue(strategy(valueToString(v)))}, true
+	}
+
+	if v.Kind() == slog.KindAny {
+		if na, did := p.redactAny(a.Key, v); did {
+			return na, true
+		}
+	}
+	return a, false
+}
+
+// redactAny reflects over a plain struct (or pointer to one), whether it arrived via
+// slog.Any or as the resolved result of a slog.LogValuer, rewriting it into a group so that
+// fields tagged `log:"redact"` / `log:"mask,lastN"` are masked.
+func (p *redactionPipeline) redactAny(key string, v slog.Value) (slog.Attr, bool) {
+	rv := reflect.ValueOf(derefAny(v.Any()))
+	if rv.Kind() != reflect.Struct {
+		return slog.Attr{}, false
+	}
+
+	fields := taggedFields(rv.Type())
+	if len(fields) == 0 {
+		return slog.Attr{}, false
+	}
+
+	t := rv.Type()
+	attrs := make([]slog.Attr, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := jsonFieldName(sf)
+		fv := rv.Field(i)
+		if strategy, ok := fields[i]; ok {
+			attrs = append(attrs, slog.String(name, strategy(fmt.Sprint(fv.Interface()))))
+			continue
+		}
+		attrs = append(attrs, slog.Any(name, fv.Interface()))
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}, true
+}
+
+// structTagCache memoizes, per reflect.Type, which field indices carry a `log` tag and the
+// strategy it selects, so repeated records for the same type skip re-parsing struct tags.
+var structTagCache sync.Map // map[reflect.Type]map[int]RedactStrategy
+
+func taggedFields(t reflect.Type) map[int]RedactStrategy {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	if cached, ok := structTagCache.Load(t); ok {
+		return cached.(map[int]RedactStrategy)
+	}
+
+	fields := make(map[int]RedactStrategy)
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("log")
+		if !ok {
+			continue
+		}
+		if strategy := parseTagStrategy(tag); strategy != nil {
+			fields[i] = strategy
+		}
+	}
+	structTagCache.Store(t, fields)
+	return fields
+}
+
+func parseTagStrategy(tag string) RedactStrategy {
+	parts := strings.Split(tag, ",")
+	switch parts[0] {
+	case "redact":
+		return RedactFull()
+	case "mask":
+		n := 4
+		for _, opt := range parts[1:] {
+			if rest, ok := strings.CutPrefix(opt, "last"); ok {
+				if v, err := strconv.Atoi(rest); err == nil {
+					n = v
+				}
+			}
+		}
+		return RedactMaskLast(n)
+	default:
+		return nil
+	}
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	tag, ok := f.Tag.Lookup("json")
+	if !ok || tag == "" {
+		return f.Name
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func derefAny(a any) any {
+	rv := reflect.ValueOf(a)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
+
+func valueToString(v slog.Value) string {
+	if v.Kind() == slog.KindString {
+		return v.String()
+	}
+	return fmt.Sprint(v.Any())
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RotatingFileHandlerOptions configures RotatingFileHandler's rotation behavior.
+type RotatingFileHandlerOptions struct {
+	// MaxSizeBytes rotates the active file once writing would exceed this size. Zero
+	// disables size-based rotation.
+	MaxSizeBytes int64
+	// MaxAge rotates the active file once it has been open longer than this. Zero disables
+	// time-based rotation.
+	MaxAge time.Duration
+	// MaxBackups is how many compressed rotated files to keep; older ones are deleted. Zero
+	// keeps every backup.
+	MaxBackups int
+	// HandlerOptions is forwarded to the underlying slog.JSONHandler.
+	HandlerOptions *slog.HandlerOptions
+}
+
+// RotatingFileHandler is a slog.Handler writing newline-delimited JSON to a file, rotating it
+// by size and/or age and gzip-compressing rotated backups.
+type RotatingFileHandler struct {
+	path string
+	opts RotatingFileHandlerOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	inner slog.Handler
+}
+
+// NewRotatingFileHandler opens (creating if necessary) the file at path and returns a handler
+// writing to it, rotating according to opts.
+func NewRotatingFileHandler(path string, opts RotatingFileHandlerOptions) (*RotatingFileHandler, error) {
+	h := &RotatingFileHandler{path: path, opts: opts}
+	if err := h.openLocked(); err != nil {
+		return nil, err
+	}
+	h.inner = slog.NewJSONHandler(h, h.opts.HandlerOptions)
+	return h, nil
+}
+
+func (h *RotatingFileHandler) openLocked() error {
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logger: opening rotating log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("logger: statting rotating log file: %w", err)
+	}
+	h.file = f
+	h.size = info.Size()
+	h.openedAt = time.Now()
+	return nil
+}
+
+// Write implements io.Writer, so RotatingFileHandler is the sink the underlying JSON handler
+// writes into; it rotates the file first if the incoming write would exceed the configured
+// size or age limit.
+func (h *RotatingFileHandler) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.shouldRotateLocked(len(p)) {
+		if err := h.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := h.file.Write(p)
+	h.size += int64(n)
+	return n, err
+}
+
+func (h *RotatingFileHandler) shouldRotateLocked(nextWrite int) bool {
+	if h.opts.MaxSizeBytes > 0 && h.size+int64(nextWrite) > h.opts.MaxSizeBytes {
+		return true
+	}
+	if h.opts.MaxAge > 0 && time.Since(h.openedAt) > h.opts.MaxAge {
+		return true
+	}
+	return false
+}
+
+func (h *RotatingFileHandler) rotateLocked() error {
+	if err := h.file.Close(); err != nil {
+		return fmt.Errorf("logger: closing rotating log file: %w", err)
+	}
+
+	rotated := fmt.Sprintf("%s.%s", h.path, time.Now().Format("20060102T150405.000000000"))
+	if err := os.Rename(h.path, rotated); err != nil {
+		return fmt.Errorf("logger: renaming rotating log file: %w", err)
+	}
+	go h.compressAndPrune(rotated)
+
+	return h.openLocked()
+}
+
+func (h *RotatingFileHandler) compressAndPrune(rotated string) {
+	if err := gzipFile(rotated); err != nil {
+		return
+	}
+	h.pruneBackups()
+}
+
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		_ = gw.Close()
+		_ = dst.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (h *RotatingFileHandler) pruneBackups() {
+	if h.opts.MaxBackups <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(h.path + ".*.gz")
+	if err != nil || len(matches) <= h.opts.MaxBackups {
+		return
+	}
+	sort.Strings(matches) // the timestamp suffix sorts chronologically
+	for _, old := range matches[:len(matches)-h.opts.MaxBackups] {
+		_ = os.Remove(old)
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *RotatingFileHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *RotatingFileHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler. The returned handler still writes through h, so
+// rotation stays correct regardless of how many derived handlers are in use.
+func (h *RotatingFileHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.inner.WithAttrs(attrs)
+}
+
+// WithGroup implements slog.Handler. The returned handler still writes through h, so
+// rotation stays correct regardless of how many derived handlers are in use.
+func (h *RotatingFileHandler) WithGroup(name string) slog.Handler {
+	return h.inner.WithGroup(name)
+}
+
+// Close flushes and closes the active file. It does not touch already-rotated backups.
+func (h *RotatingFileHandler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.file.Close()
+}
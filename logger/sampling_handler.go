@@ -0,0 +1,285 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimit configures a token-bucket: Rate tokens are added per second, up to Burst.
+type RateLimit struct {
+	Rate  float64
+	Burst float64
+}
+
+// SamplingHandlerOptions configures SamplingHandler.
+type SamplingHandlerOptions struct {
+	// AlwaysLevel records at or above this level always pass through, unsampled. Defaults to
+	// slog.LevelError.
+	AlwaysLevel slog.Leveler
+	// RateLimit, if set, enables a token-bucket limiter per (level, request path) key.
+	RateLimit *RateLimit
+	// BurstFirst/EveryNth/Window implement "log first N then every Mth" behavior per unique
+	// (level, message) template within a sliding Window: the first BurstFirst records in a
+	// window pass, then every EveryNth-th one after that, until Window elapses and the
+	// counter resets. A zero Window disables this limiter.
+	BurstFirst int
+	EveryNth   int
+	Window     time.Duration
+	// SummaryInterval, if > 0, emits a synthetic summary record on this interval per
+	// (request path, level) reporting how much was dropped_count/sampled_count since the
+	// last summary.
+	SummaryInterval time.Duration
+}
+
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	lastFill time.Time
+}
+
+func (b *tokenBucket) allow(rate, burst float64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.lastFill.IsZero() {
+		b.tokens = burst
+	} else {
+		elapsed := now.Sub(b.lastFill).Seconds()
+		b.tokens = min(burst, b.tokens+elapsed*rate)
+	}
+	b.lastFill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type messageWindow struct {
+	mu        sync.Mutex
+	windowEnd time.Time
+	count     int64
+}
+
+func (w *messageWindow) allow(now time.Time, window time.Duration, first, everyNth int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if now.After(w.windowEnd) {
+		w.windowEnd = now.Add(window)
+		w.count = 0
+	}
+	w.count++
+
+	if w.count <= int64(first) {
+		return true
+	}
+	if everyNth <= 0 {
+		return false
+	}
+	return (w.count-int64(first))%int64(everyNth) == 0
+}
+
+type counterKey struct {
+	path  string
+	level slog.Level
+}
+
+type pathLevelCounter struct {
+	dropped atomic.Int64
+	sampled atomic.Int64
+}
+
+// samplingState is the mutable, shared core behind every SamplingHandler derived from the
+// same NewSamplingHandler call (via WithAttrs/WithGroup), so rate limits and windows apply
+// across the whole logger tree rather than per derived handler.
+type samplingState struct {
+	opts SamplingHandlerOptions
+	base slog.Handler // receives synthetic summary records
+
+	bucketsMu sync.Mutex
+	buckets   map[string]*tokenBucket
+
+	windowsMu sync.Mutex
+	windows   map[string]*messageWindow
+
+	countersMu sync.Mutex
+	counters   map[counterKey]*pathLevelCounter
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// SamplingHandler decorates any slog.Handler, dropping records under load per the
+// rate-limit, sliding-window, and always-pass rules in SamplingHandlerOptions.
+type SamplingHandler struct {
+	next  slog.Handler
+	state *samplingState
+}
+
+// NewSamplingHandler builds a SamplingHandler wrapping next per opts.
+func NewSamplingHandler(next slog.Handler, opts SamplingHandlerOptions) *SamplingHandler {
+	if opts.AlwaysLevel == nil {
+		opts.AlwaysLevel = slog.LevelError
+	}
+
+	state := &samplingState{
+		opts:     opts,
+		base:     next,
+		buckets:  make(map[string]*tokenBucket),
+		windows:  make(map[string]*messageWindow),
+		counters: make(map[counterKey]*pathLevelCounter),
+		done:     make(chan struct{}),
+	}
+	if opts.SummaryInterval > 0 {
+		state.wg.Add(1)
+		go state.summaryLoop()
+	}
+	return &SamplingHandler{next: next, state: state}
+}
+
+// Enabled implements slog.Handler.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler, sampling r per the configured rules before delegating.
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	opts := h.state.opts
+	if r.Level >= opts.AlwaysLevel.Level() {
+		return h.next.Handle(ctx, r)
+	}
+
+	path := getDefaultValueFromContext(ctx, "request_path")
+
+	if opts.RateLimit != nil {
+		key := path + "|" + r.Level.String()
+		if !h.state.bucketFor(key).allow(opts.RateLimit.Rate, opts.RateLimit.Burst) {
+			h.state.drop(path, r.Level)
+			return nil
+		}
+	}
+
+	if opts.Window > 0 {
+		key := messageKey(r.Level, r.Message)
+		if !h.state.windowFor(key).allow(time.Now(), opts.Window, opts.BurstFirst, opts.EveryNth) {
+			h.state.drop(path, r.Level)
+			return nil
+		}
+	}
+
+	h.state.sample(path, r.Level)
+	return h.next.Handle(ctx, r)
+}
+
+func messageKey(level slog.Level, message string) string {
+	sum := sha256.Sum256([]byte(level.String() + "|" + message))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithAttrs implements slog.Handler, sharing the same sampling state as h.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup implements slog.Handler, sharing the same sampling state as h.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Close stops the summary loop, if one was started. It must only be called once, on the
+// handler returned by NewSamplingHandler.
+func (h *SamplingHandler) Close() error {
+	close(h.state.done)
+	h.state.wg.Wait()
+	return nil
+}
+
+func (s *samplingState) bucketFor(key string) *tokenBucket {
+	s.bucketsMu.Lock()
+	defer s.bucketsMu.Unlock()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{}
+		s.buckets[key] = b
+	}
+	return b
+}
+
+func (s *samplingState) windowFor(key string) *messageWindow {
+	s.windowsMu.Lock()
+	defer s.windowsMu.Unlock()
+	w, ok := s.windows[key]
+	if !ok {
+		w = &messageWindow{}
+		s.windows[key] = w
+	}
+	return w
+}
+
+func (s *samplingState) counterFor(path string, level slog.Level) *pathLevelCounter {
+	key := counterKey{path: path, level: level}
+	s.countersMu.Lock()
+	defer s.countersMu.Unlock()
+	c, ok := s.counters[key]
+	if !ok {
+		c = &pathLevelCounter{}
+		s.counters[key] = c
+	}
+	return c
+}
+
+func (s *samplingState) drop(path string, level slog.Level) {
+	s.counterFor(path, level).dropped.Add(1)
+}
+
+func (s *samplingState) sample(path string, level slog.Level) {
+	s.counterFor(path, level).sampled.Add(1)
+}
+
+func (s *samplingState) summaryLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.opts.SummaryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.emitSummaries()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *samplingState) emitSummaries() {
+	s.countersMu.Lock()
+	counters := make(map[counterKey]*pathLevelCounter, len(s.counters))
+	for key, c := range s.counters {
+		counters[key] = c
+	}
+	s.countersMu.Unlock()
+
+	for key, c := range counters {
+		dropped, sampled := c.dropped.Swap(0), c.sampled.Swap(0)
+		if dropped == 0 && sampled == 0 {
+			continue
+		}
+		// Use a "sampling" group rather than "meta_information": this record still flows
+		// through s.base (typically a ContextHandler), which adds its own
+		// "meta_information" group, and a second group of that name would shadow it.
+		r := slog.NewRecord(time.Now(), key.level, "sampling summary", 0)
+		r.AddAttrs(slog.Group("sampling",
+			slog.String("request_path", key.path),
+			slog.Int64("dropped_count", dropped),
+			slog.Int64("sampled_count", sampled),
+		))
+		_ = s.base.Handle(context.Background(), r)
+	}
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// traceparentRe matches a W3C Trace Context traceparent header:
+// version "-" trace-id "-" parent-id "-" trace-flags.
+var traceparentRe = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+// TraceParent is a parsed, or freshly generated, W3C Trace Context traceparent value.
+// See https://www.w3.org/TR/trace-context/#traceparent-header.
+type TraceParent struct {
+	TraceID string
+	SpanID  string
+	Flags   string
+}
+
+// ParseTraceParent parses a traceparent header value. Only version "00" is understood, as
+// specified; an unsupported version, malformed value, or all-zero trace/span id is reported
+// via ok=false so the caller can fall back to NewTraceParent.
+func ParseTraceParent(header string) (tp TraceParent, ok bool) {
+	m := traceparentRe.FindStringSubmatch(header)
+	if m == nil || m[1] != "00" {
+		return TraceParent{}, false
+	}
+	if m[2] == strings.Repeat("0", 32) || m[3] == strings.Repeat("0", 16) {
+		return TraceParent{}, false
+	}
+	return TraceParent{TraceID: m[2], SpanID: m[3], Flags: m[4]}, true
+}
+
+// NewTraceParent generates a fresh, spec-valid traceparent: a random 16-byte trace-id, a
+// random 8-byte span-id, and the sampled flag set.
+func NewTraceParent() TraceParent {
+	return TraceParent{
+		TraceID: randomHex(16),
+		SpanID:  randomHex(8),
+		Flags:   "01",
+	}
+}
+
+// String renders the traceparent in "00-<trace-id>-<span-id>-<flags>" wire format.
+func (tp TraceParent) String() string {
+	return fmt.Sprintf("00-%s-%s-%s", tp.TraceID, tp.SpanID, tp.Flags)
+}
+
+// NewChildSpan returns a TraceParent for this service's own span within the same trace: it
+// keeps tp's trace-id and flags but generates a fresh span-id, since per the W3C Trace
+// Context spec a server receiving a traceparent must mint its own span rather than reuse the
+// caller's.
+func (tp TraceParent) NewChildSpan() TraceParent {
+	return TraceParent{
+		TraceID: tp.TraceID,
+		SpanID:  randomHex(8),
+		Flags:   tp.Flags,
+	}
+}
+
+func randomHex(byteLen int) string {
+	b := make([]byte, byteLen)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand.Read does not fail on any supported platform; this keeps the
+		// function total instead of panicking if it ever does.
+		return strings.Repeat("0", byteLen*2)
+	}
+	return hex.EncodeToString(b)
+}
+
+// SpanFromContextFunc lets callers who already run an OpenTelemetry (or other tracing) SDK
+// supply the active trace/span IDs for a request, without this module importing that SDK
+// directly. Returning ok=false falls back to whatever CorrelationId/traceparent parsing put
+// in the context.
+type SpanFromContextFunc func(ctx context.Context) (traceID, spanID string, ok bool)
@@ -3,10 +3,8 @@ package main
 import (
 	"context"
 	"github.com/go-faker/faker/v4"
-	"github.com/hashicorp/go-uuid"
 	"github.com/labstack/echo"
 	"log/slog"
-	"math/rand"
 	"os"
 	"structured-logging-echo/logger"
 	"time"
@@ -19,8 +17,44 @@ func main() {
 		Level:     slog.LevelInfo,
 	}
 	jsonHandler := slog.NewJSONHandler(os.Stdout, &opts)
-	ctxHandler := logger.ContextHandler{Handler: jsonHandler}
-	logger := slog.New(ctxHandler)
+
+	fileHandler, err := logger.NewRotatingFileHandler("app.log", logger.RotatingFileHandlerOptions{
+		MaxSizeBytes: 10 * 1024 * 1024,
+		MaxAge:       24 * time.Hour,
+		MaxBackups:   5,
+	})
+	if err != nil {
+		slog.Error("failed to open rotating log file", "error", err)
+		os.Exit(1)
+	}
+
+	webhookHandler := logger.NewHTTPHandler(logger.HTTPHandlerOptions{
+		URL:           "https://logs.example.com/ingest",
+		FlushInterval: 2 * time.Second,
+		MaxBatchSize:  50,
+	})
+
+	multiHandler := logger.NewMultiHandler(
+		logger.SinkLevel{Handler: jsonHandler, Level: slog.LevelInfo},
+		logger.SinkLevel{Handler: fileHandler, Level: slog.LevelError},
+		logger.SinkLevel{Handler: webhookHandler, Level: slog.LevelWarn},
+	)
+
+	ctxHandler := logger.NewContextHandler(multiHandler, logger.WithRedactKeys("*.secret", "branch-secret", "authorization"))
+	samplingHandler := logger.NewSamplingHandler(ctxHandler, logger.SamplingHandlerOptions{
+		AlwaysLevel:     slog.LevelError,
+		RateLimit:       &logger.RateLimit{Rate: 10, Burst: 20},
+		BurstFirst:      5,
+		EveryNth:        10,
+		Window:          time.Minute,
+		SummaryInterval: 30 * time.Second,
+	})
+	asyncHandler := logger.NewAsyncHandler(samplingHandler, logger.AsyncHandlerOptions{
+		QueueSize:      1024,
+		Workers:        2,
+		OverflowPolicy: logger.DropOldest,
+	})
+	logger := slog.New(asyncHandler)
 	slog.SetDefault(logger)
 
 	e := echo.New()
@@ -60,25 +94,51 @@ func (c Customer) LogValue() slog.Value {
 type Bank struct {
 	BranchId     int        `json:"branch_id"`
 	BranchName   string     `json:"branch_name"`
-	BranchSecret string     `json:"branch-secret"`
+	BranchSecret string     `json:"branch-secret" log:"redact"`
 	Customers    []Customer `json:"customers"`
 }
 
 func (b Bank) LogValue() slog.Value {
-	// it will return a single value, so the output will be another field
-	return slog.IntValue(b.BranchId)
+	// branch-secret is redacted automatically by the ContextHandler's key-based
+	// redaction rules (see logger.WithRedactKeys in main), so it is safe to include here.
+	var attributes []slog.Attr
+	attributes = append(attributes, slog.Int("branch_id", b.BranchId))
+	attributes = append(attributes, slog.String("branch_name", b.BranchName))
+	attributes = append(attributes, slog.String("branch-secret", b.BranchSecret))
+	return slog.GroupValue(attributes...)
 }
 
-// CorrelationId adding correlation id in context
+// CorrelationId adding correlation id in context. It interoperates with W3C Trace Context
+// (https://www.w3.org/TR/trace-context/): if the inbound request carries a traceparent
+// header, its trace-id becomes the correlation_id and this service mints its own span-id for
+// that trace (recording the caller's span-id as parent_span_id), per the spec's requirement
+// that a server not reuse the caller's span; otherwise a fresh traceparent is generated. The
+// resulting traceparent is returned on the response so downstream services can join the same
+// trace, and tracestate, if present, is echoed back unchanged.
 func CorrelationId(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		requestId, err := uuid.GenerateUUID()
-		if err != nil {
-			slog.ErrorContext(c.Request().Context(), "Error in generating unique correlation id "+err.Error())
-			// generating a random string of 32
-			requestId = randomString(32)
+		var tp logger.TraceParent
+		var parentSpanID string
+		if inbound, ok := logger.ParseTraceParent(c.Request().Header.Get("traceparent")); ok {
+			tp = inbound.NewChildSpan()
+			parentSpanID = inbound.SpanID
+		} else {
+			tp = logger.NewTraceParent()
 		}
-		ctx := context.WithValue(c.Request().Context(), "correlation_id", requestId)
+
+		ctx := context.WithValue(c.Request().Context(), "correlation_id", tp.TraceID)
+		ctx = context.WithValue(ctx, "trace_id", tp.TraceID)
+		ctx = context.WithValue(ctx, "span_id", tp.SpanID)
+		ctx = context.WithValue(ctx, "trace_flags", tp.Flags)
+		if parentSpanID != "" {
+			ctx = context.WithValue(ctx, "parent_span_id", parentSpanID)
+		}
+
+		c.Response().Header().Set("traceparent", tp.String())
+		if state := c.Request().Header.Get("tracestate"); state != "" {
+			c.Response().Header().Set("tracestate", state)
+		}
+
 		request := c.Request().Clone(ctx)
 		c.SetRequest(request)
 		return next(c)
@@ -99,17 +159,3 @@ func AddRouteMetaData(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(c)
 	}
 }
-
-// Function to generate a random string of a given length
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	seededRand := rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	// Create a byte slice of the required length
-	randomBytes := make([]byte, length)
-	for i := range randomBytes {
-		randomBytes[i] = charset[seededRand.Intn(len(charset))]
-	}
-
-	return string(randomBytes)
-}